@@ -0,0 +1,174 @@
+package goaxle
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultBulkConcurrency is used when BulkOptions.Concurrency is left at
+// zero.
+const defaultBulkConcurrency = 4
+
+// BulkOptions controls the concurrency and failure handling of the Bulk*
+// operations.
+type BulkOptions struct {
+	// Concurrency is the number of requests allowed in flight at once.
+	// Defaults to defaultBulkConcurrency when <= 0.
+	Concurrency int
+	// StopOnError, when true, stops issuing new requests as soon as one
+	// fails. Requests already in flight are allowed to finish.
+	StopOnError bool
+}
+
+func (o BulkOptions) concurrency() int {
+	if o.Concurrency <= 0 {
+		return defaultBulkConcurrency
+	}
+	return o.Concurrency
+}
+
+// BulkSaveKeyRings saves each of keyRings against the ApiAxle server at
+// axleAddress, running up to opts.Concurrency requests at a time over a
+// single keep-alive http.Client. It returns a map from KeyRing identifier to
+// the error (if any) encountered saving it.
+func BulkSaveKeyRings(ctx context.Context, axleAddress string, keyRings []*KeyRing, opts BulkOptions) (map[string]error, error) {
+	client := NewKeyRingClient(axleAddress)
+
+	sem := make(chan struct{}, opts.concurrency())
+	results := make(map[string]error, len(keyRings))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var stopped bool
+
+	for _, keyRing := range keyRings {
+		sem <- struct{}{}
+		mu.Lock()
+		halt := stopped
+		mu.Unlock()
+		if halt {
+			<-sem
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(keyRing *KeyRing) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			_, err := client.Save(ctx, keyRing)
+
+			mu.Lock()
+			results[keyRing.Identifier] = err
+			if err != nil && opts.StopOnError {
+				stopped = true
+			}
+			mu.Unlock()
+		}(keyRing)
+	}
+
+	wg.Wait()
+
+	return results, ctx.Err()
+}
+
+// BulkDeleteKeyRings deletes each of identifiers from the ApiAxle server at
+// axleAddress, running up to opts.Concurrency requests at a time. It returns
+// a map from identifier to the error (if any) encountered deleting it.
+func BulkDeleteKeyRings(ctx context.Context, axleAddress string, identifiers []string, opts BulkOptions) (map[string]error, error) {
+	client := NewKeyRingClient(axleAddress)
+
+	sem := make(chan struct{}, opts.concurrency())
+	results := make(map[string]error, len(identifiers))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var stopped bool
+
+	for _, identifier := range identifiers {
+		sem <- struct{}{}
+		mu.Lock()
+		halt := stopped
+		mu.Unlock()
+		if halt {
+			<-sem
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(identifier string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := client.Delete(ctx, identifier)
+
+			mu.Lock()
+			results[identifier] = err
+			if err != nil && opts.StopOnError {
+				stopped = true
+			}
+			mu.Unlock()
+		}(identifier)
+	}
+
+	wg.Wait()
+
+	return results, ctx.Err()
+}
+
+// BulkLinkKeys links each of keyIdentifiers to this KeyRing against client,
+// running up to opts.Concurrency requests at a time. It returns the linked
+// Key (absent on failure) and the error (if any) for each requested key
+// identifier.
+func (this *KeyRing) BulkLinkKeys(ctx context.Context, client KeyRingClient, keyIdentifiers []string, opts BulkOptions) (map[string]*Key, map[string]error, error) {
+	sem := make(chan struct{}, opts.concurrency())
+	keys := make(map[string]*Key, len(keyIdentifiers))
+	errs := make(map[string]error, len(keyIdentifiers))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var stopped bool
+
+	for _, keyIdentifier := range keyIdentifiers {
+		sem <- struct{}{}
+		mu.Lock()
+		halt := stopped
+		mu.Unlock()
+		if halt {
+			<-sem
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(keyIdentifier string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			key, err := client.LinkKey(ctx, this.Identifier, keyIdentifier)
+
+			mu.Lock()
+			if err != nil {
+				errs[keyIdentifier] = err
+				if opts.StopOnError {
+					stopped = true
+				}
+			} else {
+				keys[keyIdentifier] = key
+			}
+			mu.Unlock()
+		}(keyIdentifier)
+	}
+
+	wg.Wait()
+
+	return keys, errs, ctx.Err()
+}
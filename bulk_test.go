@@ -0,0 +1,221 @@
+package goaxle
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newBulkTestServer(t *testing.T, handler http.HandlerFunc) string {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv.URL + "/"
+}
+
+func TestBulkSaveKeyRingsRespectsConcurrencyLimit(t *testing.T) {
+	const concurrency = 3
+
+	var inFlight, maxInFlight int32
+	release := make(chan struct{})
+	addr := newBulkTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		cur := atomic.AddInt32(&inFlight, 1)
+		for {
+			old := atomic.LoadInt32(&maxInFlight)
+			if cur <= old || atomic.CompareAndSwapInt32(&maxInFlight, old, cur) {
+				break
+			}
+		}
+		<-release
+		atomic.AddInt32(&inFlight, -1)
+		w.Write([]byte(`{"results":{"createdAt":1,"updatedAt":1}}`))
+	})
+
+	keyRings := make([]*KeyRing, 10)
+	for i := range keyRings {
+		keyRings[i] = NewKeyRing(string(rune('a' + i)))
+	}
+
+	done := make(chan struct{})
+	go func() {
+		BulkSaveKeyRings(context.Background(), addr, keyRings, BulkOptions{Concurrency: concurrency})
+		close(done)
+	}()
+
+	// Give the worker pool a chance to saturate before releasing requests.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	<-done
+
+	if got := atomic.LoadInt32(&maxInFlight); got > concurrency {
+		t.Fatalf("observed %d requests in flight at once, want <= %d", got, concurrency)
+	}
+}
+
+func TestBulkDeleteKeyRingsStopsIssuingAfterError(t *testing.T) {
+	var requests int32
+	addr := newBulkTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{"results":{"error":{"type":"KeyringUnknown","message":"no such keyring"}}}`))
+	})
+
+	identifiers := make([]string, 20)
+	for i := range identifiers {
+		identifiers[i] = string(rune('a' + i))
+	}
+
+	results, _ := BulkDeleteKeyRings(context.Background(), addr, identifiers, BulkOptions{
+		Concurrency: 2,
+		StopOnError: true,
+	})
+
+	if got := atomic.LoadInt32(&requests); int(got) >= len(identifiers) {
+		t.Fatalf("issued %d requests, want StopOnError to short-circuit before exhausting all %d", got, len(identifiers))
+	}
+	if len(results) == 0 {
+		t.Fatal("results is empty, want at least the in-flight errors recorded")
+	}
+}
+
+func TestBulkSaveKeyRingsWaitsForInFlightOnCancel(t *testing.T) {
+	var inFlight int32
+	release := make(chan struct{})
+	addr := newBulkTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&inFlight, 1)
+		<-release
+		atomic.AddInt32(&inFlight, -1)
+		w.Write([]byte(`{"results":{"createdAt":1,"updatedAt":1}}`))
+	})
+
+	keyRings := make([]*KeyRing, 5)
+	for i := range keyRings {
+		keyRings[i] = NewKeyRing(string(rune('a' + i)))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		BulkSaveKeyRings(ctx, addr, keyRings, BulkOptions{Concurrency: 5})
+		close(done)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	close(release)
+	<-done
+
+	// If BulkSaveKeyRings returned before its goroutines finished, this
+	// would still observe requests in flight.
+	if got := atomic.LoadInt32(&inFlight); got != 0 {
+		t.Fatalf("%d requests still in flight after BulkSaveKeyRings returned, want 0", got)
+	}
+}
+
+func TestKeyRingBulkLinkKeysRespectsConcurrencyLimit(t *testing.T) {
+	const concurrency = 3
+
+	var inFlight, maxInFlight int32
+	release := make(chan struct{})
+	addr := newBulkTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		cur := atomic.AddInt32(&inFlight, 1)
+		for {
+			old := atomic.LoadInt32(&maxInFlight)
+			if cur <= old || atomic.CompareAndSwapInt32(&maxInFlight, old, cur) {
+				break
+			}
+		}
+		<-release
+		atomic.AddInt32(&inFlight, -1)
+		w.Write([]byte(`{"results":{}}`))
+	})
+
+	ring := NewKeyRing("my-ring")
+	client := NewKeyRingClient(addr)
+	keyIdentifiers := make([]string, 10)
+	for i := range keyIdentifiers {
+		keyIdentifiers[i] = string(rune('a' + i))
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ring.BulkLinkKeys(context.Background(), client, keyIdentifiers, BulkOptions{Concurrency: concurrency})
+		close(done)
+	}()
+
+	// Give the worker pool a chance to saturate before releasing requests.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	<-done
+
+	if got := atomic.LoadInt32(&maxInFlight); got > concurrency {
+		t.Fatalf("observed %d requests in flight at once, want <= %d", got, concurrency)
+	}
+}
+
+func TestKeyRingBulkLinkKeysStopsIssuingAfterError(t *testing.T) {
+	var requests int32
+	addr := newBulkTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{"results":{"error":{"type":"KeyringUnknown","message":"no such keyring"}}}`))
+	})
+
+	ring := NewKeyRing("my-ring")
+	client := NewKeyRingClient(addr)
+	keyIdentifiers := make([]string, 20)
+	for i := range keyIdentifiers {
+		keyIdentifiers[i] = string(rune('a' + i))
+	}
+
+	_, errs, _ := ring.BulkLinkKeys(context.Background(), client, keyIdentifiers, BulkOptions{
+		Concurrency: 2,
+		StopOnError: true,
+	})
+
+	if got := atomic.LoadInt32(&requests); int(got) >= len(keyIdentifiers) {
+		t.Fatalf("issued %d requests, want StopOnError to short-circuit before exhausting all %d", got, len(keyIdentifiers))
+	}
+	if len(errs) == 0 {
+		t.Fatal("errs is empty, want at least the in-flight errors recorded")
+	}
+}
+
+func TestKeyRingBulkLinkKeysWaitsForInFlightOnCancel(t *testing.T) {
+	var inFlight int32
+	release := make(chan struct{})
+	addr := newBulkTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&inFlight, 1)
+		<-release
+		atomic.AddInt32(&inFlight, -1)
+		w.Write([]byte(`{"results":{}}`))
+	})
+
+	ring := NewKeyRing("my-ring")
+	client := NewKeyRingClient(addr)
+	keyIdentifiers := make([]string, 5)
+	for i := range keyIdentifiers {
+		keyIdentifiers[i] = string(rune('a' + i))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		ring.BulkLinkKeys(ctx, client, keyIdentifiers, BulkOptions{Concurrency: 5})
+		close(done)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	close(release)
+	<-done
+
+	// If BulkLinkKeys returned before its goroutines finished, this would
+	// still observe requests in flight.
+	if got := atomic.LoadInt32(&inFlight); got != 0 {
+		t.Fatalf("%d requests still in flight after BulkLinkKeys returned, want 0", got)
+	}
+}
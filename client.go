@@ -0,0 +1,257 @@
+package goaxle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// KeyRingClient performs KeyRing operations against an ApiAxle server. It
+// exists so that code which depends on go-axle can substitute a fake or a
+// generated mock (see the mocks subpackage) in place of NewKeyRingClient's
+// HTTP-backed implementation when under test.
+type KeyRingClient interface {
+	// Save creates keyRing on the server and returns the resulting
+	// KeyRing. Updating a KeyRing that already exists (one retrieved with
+	// Get, or one already saved) is not supported and returns an error.
+	Save(ctx context.Context, keyRing *KeyRing) (*KeyRing, error)
+	// Get retrieves the KeyRing identified by identifier.
+	Get(ctx context.Context, identifier string) (*KeyRing, error)
+	// List returns the KeyRings in the [from, to] window.
+	List(ctx context.Context, from int, to int) ([]*KeyRing, error)
+	// Delete removes the KeyRing identified by identifier.
+	Delete(ctx context.Context, identifier string) error
+	// LinkKey associates keyIdentifier with the keyRingIdentifier KeyRing.
+	LinkKey(ctx context.Context, keyRingIdentifier string, keyIdentifier string) (*Key, error)
+	// UnlinkKey disassociates keyIdentifier from the keyRingIdentifier
+	// KeyRing.
+	UnlinkKey(ctx context.Context, keyRingIdentifier string, keyIdentifier string) (*Key, error)
+	// Keys lists the Keys linked to keyRingIdentifier in the [from, to]
+	// window.
+	Keys(ctx context.Context, keyRingIdentifier string, from int, to int) ([]*Key, error)
+	// Stats returns hit counts for keyRingIdentifier, optionally narrowed
+	// to a single api/key, bucketed at granularity.
+	Stats(ctx context.Context, keyRingIdentifier string, from time.Time, to time.Time, forapi string, forkey string, granularity Granularity) (map[HitType]map[time.Time]map[int]int, error)
+}
+
+// NewKeyRingClient returns the default, HTTP-backed KeyRingClient for the
+// ApiAxle server at axleAddress.
+func NewKeyRingClient(axleAddress string) KeyRingClient {
+	return &httpKeyRingClient{axleAddress: axleAddress}
+}
+
+// httpKeyRingClient is the production KeyRingClient. It owns every piece of
+// transport state (the server address) that KeyRing values used to carry
+// around themselves.
+type httpKeyRingClient struct {
+	axleAddress string
+}
+
+func (c *httpKeyRingClient) Save(ctx context.Context, keyRing *KeyRing) (*KeyRing, error) {
+	reqAddress := fmt.Sprintf(
+		"%s%skeyring/%s",
+		c.axleAddress,
+		VERSION_ENDPOINT,
+		url.QueryEscape(keyRing.Identifier),
+	)
+
+	// update the updatedAt timestamp
+	keyRing.UpdatedAt = float64(time.Now().UnixNano() / (1000 * 1000))
+	marshalled, err := json.Marshal(keyRing)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to marshal KeyRing: %s", err.Error())
+	}
+
+	if !keyRing.createOnSave {
+		// TODO: why have an last updated field if you can't update it?
+		return nil, fmt.Errorf("Unable to update key rings, it's not yet supported")
+	}
+
+	body, err := doHttpRequestContext(ctx, "POST", reqAddress, marshalled)
+	if err != nil {
+		return nil, err
+	}
+
+	out := keyRing
+	if err := populateKeyRingFromResponse(&out, body, []string{"results"}); err != nil {
+		return nil, err
+	}
+	out.createOnSave = false
+
+	return out, nil
+}
+
+func (c *httpKeyRingClient) Get(ctx context.Context, identifier string) (*KeyRing, error) {
+	reqAddress := fmt.Sprintf("%s%skeyring/%s", c.axleAddress, VERSION_ENDPOINT, url.QueryEscape(identifier))
+	body, err := doHttpRequestContext(ctx, "GET", reqAddress, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	keyRing := NewKeyRing(identifier)
+	if err := populateKeyRingFromResponse(&keyRing, body, []string{"results"}); err != nil {
+		return nil, err
+	}
+	keyRing.createOnSave = false
+
+	return keyRing, nil
+}
+
+func (c *httpKeyRingClient) List(ctx context.Context, from int, to int) ([]*KeyRing, error) {
+	reqAddress := fmt.Sprintf(
+		"%s%skeyrings?resolve=true&from=%d&to=%d",
+		c.axleAddress,
+		VERSION_ENDPOINT,
+		from,
+		to,
+	)
+
+	body, err := doHttpRequestContext(ctx, "GET", reqAddress, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if axleErr := parseAxleError(body); axleErr != nil {
+		return nil, axleErr
+	}
+
+	response := make(map[string]interface{})
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("Unable to unmarshal response: %s", err.Error())
+	}
+	response, validCast := response["results"].(map[string]interface{})
+	if !validCast {
+		return nil, fmt.Errorf("Unable to unmarshal response: results was not an object")
+	}
+
+	out := make([]*KeyRing, len(response))
+	x := 0
+	for identifier, value := range response {
+		keyring := NewKeyRing(identifier)
+		jsonvalue, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to decode keyring in response: %s", err.Error())
+		}
+		if err := json.Unmarshal(jsonvalue, keyring); err != nil {
+			return nil, fmt.Errorf("Unable to decode keyring in response: %s", err.Error())
+		}
+		out[x] = keyring
+		x++
+	}
+
+	return out, nil
+}
+
+func (c *httpKeyRingClient) Delete(ctx context.Context, identifier string) error {
+	reqAddress := fmt.Sprintf("%s%skeyring/%s", c.axleAddress, VERSION_ENDPOINT, url.QueryEscape(identifier))
+
+	body, err := doHttpRequestContext(ctx, "DELETE", reqAddress, nil)
+	if err != nil {
+		return err
+	}
+
+	if axleErr := parseAxleError(body); axleErr != nil {
+		return axleErr
+	}
+
+	responseMap := make(map[string]interface{})
+	if err := json.Unmarshal(body, &responseMap); err != nil {
+		return fmt.Errorf("Unable to unmarshal response from %s: %s", reqAddress, err.Error())
+	}
+
+	resultsInterface, exists := responseMap["results"]
+	if !exists {
+		return fmt.Errorf("Missing response from %s", reqAddress)
+	}
+	succeeded, isValidCast := resultsInterface.(bool)
+	if !isValidCast {
+		return fmt.Errorf("Unable to extract response object from %s", reqAddress)
+	}
+	if !succeeded {
+		return &AxleError{Type: "DeleteFailed", Message: fmt.Sprintf("delete of KeyRing at %s failed", reqAddress)}
+	}
+
+	return nil
+}
+
+func (c *httpKeyRingClient) LinkKey(ctx context.Context, keyRingIdentifier string, keyIdentifier string) (*Key, error) {
+	reqAddress := fmt.Sprintf(
+		"%s%skeyring/%s/linkkey/%s",
+		c.axleAddress,
+		VERSION_ENDPOINT,
+		url.QueryEscape(keyRingIdentifier),
+		url.QueryEscape(keyIdentifier),
+	)
+
+	body, err := doHttpRequestContext(ctx, "PUT", reqAddress, []byte("{}"))
+	if err != nil {
+		return nil, err
+	}
+
+	key := NewKey(c.axleAddress, keyIdentifier)
+	if err := populateKeyFromResponse(&key, body, []string{"results"}); err != nil {
+		return nil, err
+	}
+	key.createOnSave = false
+
+	return key, nil
+}
+
+func (c *httpKeyRingClient) UnlinkKey(ctx context.Context, keyRingIdentifier string, keyIdentifier string) (*Key, error) {
+	reqAddress := fmt.Sprintf(
+		"%s%skeyring/%s/unlinkkey/%s",
+		c.axleAddress,
+		VERSION_ENDPOINT,
+		url.QueryEscape(keyRingIdentifier),
+		url.QueryEscape(keyIdentifier),
+	)
+
+	body, err := doHttpRequestContext(ctx, "PUT", reqAddress, []byte("{}"))
+	if err != nil {
+		return nil, err
+	}
+
+	key := NewKey(c.axleAddress, keyIdentifier)
+	if err := populateKeyFromResponse(&key, body, []string{"results"}); err != nil {
+		return nil, err
+	}
+	key.createOnSave = false
+
+	return key, nil
+}
+
+func (c *httpKeyRingClient) Keys(ctx context.Context, keyRingIdentifier string, from int, to int) ([]*Key, error) {
+	reqAddress := fmt.Sprintf(
+		"%s%skeyring/%s/keys?resolve=true&from=%d&to=%d",
+		c.axleAddress,
+		VERSION_ENDPOINT,
+		url.QueryEscape(keyRingIdentifier),
+		from,
+		to,
+	)
+
+	return doKeysRequestContext(ctx, reqAddress, c.axleAddress)
+}
+
+func (c *httpKeyRingClient) Stats(ctx context.Context, keyRingIdentifier string, from time.Time, to time.Time, forapi string, forkey string, granularity Granularity) (map[HitType]map[time.Time]map[int]int, error) {
+	reqAddress := fmt.Sprintf(
+		"%s%skeyring/%s/stats?from=%d&to=%d&granularity=%s",
+		c.axleAddress,
+		VERSION_ENDPOINT,
+		url.QueryEscape(keyRingIdentifier),
+		from.Unix(),
+		to.Unix(),
+		granularity,
+	)
+
+	if forkey != "" {
+		reqAddress += "&forkey=" + url.QueryEscape(forkey)
+	}
+	if forapi != "" {
+		reqAddress += "&forapi=" + url.QueryEscape(forapi)
+	}
+
+	return doStatsRequestContext(ctx, reqAddress)
+}
@@ -0,0 +1,125 @@
+package goaxle
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// AxleError represents a failure reported by the ApiAxle server, or a
+// transport-level fault encountered while talking to it. Use errors.Is/As to
+// test for one of the sentinel values below rather than matching on the
+// error string.
+type AxleError struct {
+	// Code is the numeric status_code ApiAxle reported in the response
+	// envelope's "meta" section. Zero when the error originated locally
+	// (e.g. a network fault) rather than from a parsed response.
+	Code int
+	// Type is the kind of error. For an ApiAxle error type listed in
+	// axleErrorTypes it is the matching sentinel's Type (e.g. "NotFound"
+	// for "ApiUnknown"); any other ApiAxle type is kept as reported.
+	Type string
+	// Message is the human readable message ApiAxle returned.
+	Message string
+	// Details carries any additional context ApiAxle attached to the error.
+	Details string
+
+	err error
+}
+
+// Error implements the error interface.
+func (e *AxleError) Error() string {
+	if e.Details != "" {
+		return fmt.Sprintf("go-axle: %s: %s (%s)", e.Type, e.Message, e.Details)
+	}
+	return fmt.Sprintf("go-axle: %s: %s", e.Type, e.Message)
+}
+
+// Unwrap exposes the underlying transport error, if any, so that
+// errors.Is/errors.As can see through an AxleError raised for a network
+// fault rather than a parsed ApiAxle response.
+func (e *AxleError) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether e represents the same kind of failure as target. Two
+// AxleErrors are considered the same kind when they share a Type; this lets
+// callers compare against the sentinel values below with errors.Is even
+// though the Message/Details/Code of the concrete error will differ.
+func (e *AxleError) Is(target error) bool {
+	t, ok := target.(*AxleError)
+	if !ok {
+		return false
+	}
+	return e.Type == t.Type
+}
+
+// Sentinel errors for the ApiAxle error types callers most commonly need to
+// branch on. Match with errors.Is(err, goaxle.ErrNotFound).
+var (
+	// ErrNotFound is returned when the requested api, keyring or key does
+	// not exist on the ApiAxle server.
+	ErrNotFound = &AxleError{Type: "NotFound"}
+	// ErrAlreadyExists is returned when attempting to create an api,
+	// keyring or key that already exists.
+	ErrAlreadyExists = &AxleError{Type: "AlreadyExists"}
+	// ErrUnauthorized is returned when the calling key is not permitted to
+	// perform the requested operation.
+	ErrUnauthorized = &AxleError{Type: "Unauthorized"}
+	// ErrValidation is returned when ApiAxle rejected the request body as
+	// malformed or out of range.
+	ErrValidation = &AxleError{Type: "Validation"}
+)
+
+// axleErrorTypes maps the ApiAxle error "type" string carried in a response
+// envelope to the sentinel it corresponds to. Types not present here still
+// produce a usable *AxleError, just without a sentinel match.
+var axleErrorTypes = map[string]*AxleError{
+	"ApiUnknown":     ErrNotFound,
+	"KeyringUnknown": ErrNotFound,
+	"KeyUnknown":     ErrNotFound,
+	"ApiExists":      ErrAlreadyExists,
+	"KeyringExists":  ErrAlreadyExists,
+	"KeyExists":      ErrAlreadyExists,
+	"Unauthorized":   ErrUnauthorized,
+	"InvalidKey":     ErrUnauthorized,
+	"KeyError":       ErrValidation,
+}
+
+// parseAxleError inspects a raw ApiAxle response body for the
+// {"meta":{"status_code":...},"results":{"error":{"type":"...","message":"..."}}}
+// error envelope. It returns nil when body does not describe an error, so
+// callers can fall through to decoding the successful response shape.
+func parseAxleError(body []byte) *AxleError {
+	var envelope struct {
+		Meta struct {
+			StatusCode int `json:"status_code"`
+		} `json:"meta"`
+		Results struct {
+			Error struct {
+				Type    string `json:"type"`
+				Message string `json:"message"`
+				Details string `json:"details"`
+			} `json:"error"`
+		} `json:"results"`
+	}
+
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil
+	}
+
+	if envelope.Results.Error.Type == "" && envelope.Results.Error.Message == "" {
+		return nil
+	}
+
+	axleErr := &AxleError{
+		Code:    envelope.Meta.StatusCode,
+		Type:    envelope.Results.Error.Type,
+		Message: envelope.Results.Error.Message,
+		Details: envelope.Results.Error.Details,
+	}
+	if sentinel, known := axleErrorTypes[axleErr.Type]; known {
+		axleErr.Type = sentinel.Type
+	}
+
+	return axleErr
+}
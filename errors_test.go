@@ -0,0 +1,52 @@
+package goaxle
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseAxleErrorNoEnvelope(t *testing.T) {
+	if err := parseAxleError([]byte(`{"results":{"foo":"bar"}}`)); err != nil {
+		t.Fatalf("parseAxleError() = %v, want nil for a non-error body", err)
+	}
+}
+
+func TestParseAxleErrorMapsKnownTypes(t *testing.T) {
+	cases := []struct {
+		responseType string
+		wantSentinel *AxleError
+	}{
+		{"ApiUnknown", ErrNotFound},
+		{"KeyringUnknown", ErrNotFound},
+		{"KeyExists", ErrAlreadyExists},
+		{"Unauthorized", ErrUnauthorized},
+		{"KeyError", ErrValidation},
+	}
+
+	for _, c := range cases {
+		body := []byte(`{"meta":{"status_code":409},"results":{"error":{"type":"` + c.responseType + `","message":"boom"}}}`)
+		err := parseAxleError(body)
+		if err == nil {
+			t.Fatalf("parseAxleError(%q) = nil, want an error", c.responseType)
+		}
+		if !errors.Is(err, c.wantSentinel) {
+			t.Errorf("parseAxleError(%q) = %v, not errors.Is %v", c.responseType, err, c.wantSentinel)
+		}
+		if err.Message != "boom" {
+			t.Errorf("parseAxleError(%q).Message = %q, want %q", c.responseType, err.Message, "boom")
+		}
+	}
+}
+
+func TestParseAxleErrorUnknownTypeHasNoSentinel(t *testing.T) {
+	body := []byte(`{"results":{"error":{"type":"SomethingNew","message":"boom"}}}`)
+	err := parseAxleError(body)
+	if err == nil {
+		t.Fatal("parseAxleError() = nil, want an error")
+	}
+	for _, sentinel := range []*AxleError{ErrNotFound, ErrAlreadyExists, ErrUnauthorized, ErrValidation} {
+		if errors.Is(err, sentinel) {
+			t.Errorf("parseAxleError() unexpectedly matches sentinel %v", sentinel)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package goaxle
+
+import "context"
+
+// defaultIterPageSize is used when IterOptions.PageSize is left at zero.
+const defaultIterPageSize = 50
+
+// IterOptions configures a KeyRingIterator or KeyIterator returned by
+// IterKeyRings / IterKeys.
+type IterOptions struct {
+	// PageSize is the number of items requested per page. Defaults to
+	// defaultIterPageSize when <= 0.
+	PageSize int
+	// Start is the offset the first page is fetched from.
+	Start int
+	// Prefetch, when true, fetches the next page in a goroutine while the
+	// caller is still consuming the current one.
+	Prefetch bool
+}
+
+func (o IterOptions) pageSize() int {
+	if o.PageSize <= 0 {
+		return defaultIterPageSize
+	}
+	return o.PageSize
+}
+
+// KeyRingIterator walks the KeyRings of an ApiAxle server page by page,
+// fetching additional pages on demand so callers never have to hand-roll a
+// from/to loop. A KeyRingIterator is not safe for concurrent use.
+type KeyRingIterator struct {
+	client KeyRingClient
+	opts   IterOptions
+
+	offset int
+	page   []*KeyRing
+	idx    int
+	value  *KeyRing
+	done   bool
+	err    error
+
+	prefetch chan pagedKeyRings
+}
+
+type pagedKeyRings struct {
+	page []*KeyRing
+	err  error
+}
+
+// IterKeyRings returns a KeyRingIterator over every KeyRing reachable through
+// client.
+func IterKeyRings(client KeyRingClient, opts IterOptions) *KeyRingIterator {
+	return &KeyRingIterator{
+		client: client,
+		opts:   opts,
+		offset: opts.Start,
+	}
+}
+
+// Next advances the iterator to the next KeyRing, fetching another page from
+// the server if the current one has been exhausted. It returns false once
+// the server runs out of KeyRings or ctx is cancelled; callers should then
+// inspect Err to distinguish the two.
+func (it *KeyRingIterator) Next(ctx context.Context) bool {
+	if it.done {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		it.err = err
+		it.done = true
+		return false
+	}
+
+	for it.idx >= len(it.page) {
+		if it.page != nil && len(it.page) < it.opts.pageSize() {
+			// A short page means this is the last one; Next still
+			// returns the items it contains before reporting done.
+			it.done = true
+			return false
+		}
+		page, err := it.nextPage(ctx)
+		if err != nil {
+			it.err = err
+			it.done = true
+			return false
+		}
+		it.page = page
+		it.idx = 0
+		it.offset += len(page)
+		if len(page) == 0 {
+			it.done = true
+			return false
+		}
+	}
+
+	it.value = it.page[it.idx]
+	it.idx++
+	return true
+}
+
+func (it *KeyRingIterator) nextPage(ctx context.Context) ([]*KeyRing, error) {
+	if it.opts.Prefetch {
+		if it.prefetch == nil {
+			it.startPrefetch(ctx, it.offset)
+		}
+		result := <-it.prefetch
+		if result.err == nil && len(result.page) == it.opts.pageSize() {
+			it.startPrefetch(ctx, it.offset+len(result.page))
+		} else {
+			it.prefetch = nil
+		}
+		return result.page, result.err
+	}
+
+	return it.client.List(ctx, it.offset, it.offset+it.opts.pageSize()-1)
+}
+
+func (it *KeyRingIterator) startPrefetch(ctx context.Context, offset int) {
+	it.prefetch = make(chan pagedKeyRings, 1)
+	go func(offset int) {
+		page, err := it.client.List(ctx, offset, offset+it.opts.pageSize()-1)
+		it.prefetch <- pagedKeyRings{page: page, err: err}
+	}(offset)
+}
+
+// Value returns the KeyRing the most recent call to Next advanced to. It is
+// only valid after a call to Next that returned true.
+func (it *KeyRingIterator) Value() *KeyRing {
+	return it.value
+}
+
+// Err returns the error, if any, that caused Next to return false. A nil Err
+// after Next returns false means the iterator was simply exhausted.
+func (it *KeyRingIterator) Err() error {
+	return it.err
+}
+
+// Close stops the iterator, discarding any in-flight prefetch.
+func (it *KeyRingIterator) Close() {
+	it.done = true
+	it.prefetch = nil
+}
+
+// KeyIterator walks the Keys belonging to a KeyRing page by page.
+type KeyIterator struct {
+	client            KeyRingClient
+	keyRingIdentifier string
+	opts              IterOptions
+
+	offset int
+	page   []*Key
+	idx    int
+	value  *Key
+	done   bool
+	err    error
+
+	prefetch chan pagedKeys
+}
+
+type pagedKeys struct {
+	page []*Key
+	err  error
+}
+
+// IterKeys returns a KeyIterator over every Key linked to this KeyRing.
+func (this *KeyRing) IterKeys(client KeyRingClient, opts IterOptions) *KeyIterator {
+	return &KeyIterator{
+		client:            client,
+		keyRingIdentifier: this.Identifier,
+		opts:              opts,
+		offset:            opts.Start,
+	}
+}
+
+// Next advances the iterator to the next Key, fetching another page from the
+// server if the current one has been exhausted. It returns false once the
+// server runs out of Keys or ctx is cancelled; callers should then inspect
+// Err to distinguish the two.
+func (it *KeyIterator) Next(ctx context.Context) bool {
+	if it.done {
+		return false
+	}
+	if err := ctx.Err(); err != nil {
+		it.err = err
+		it.done = true
+		return false
+	}
+
+	for it.idx >= len(it.page) {
+		if it.page != nil && len(it.page) < it.opts.pageSize() {
+			it.done = true
+			return false
+		}
+		page, err := it.nextPage(ctx)
+		if err != nil {
+			it.err = err
+			it.done = true
+			return false
+		}
+		it.page = page
+		it.idx = 0
+		it.offset += len(page)
+		if len(page) == 0 {
+			it.done = true
+			return false
+		}
+	}
+
+	it.value = it.page[it.idx]
+	it.idx++
+	return true
+}
+
+func (it *KeyIterator) nextPage(ctx context.Context) ([]*Key, error) {
+	if it.opts.Prefetch {
+		if it.prefetch == nil {
+			it.startPrefetch(ctx, it.offset)
+		}
+		result := <-it.prefetch
+		if result.err == nil && len(result.page) == it.opts.pageSize() {
+			it.startPrefetch(ctx, it.offset+len(result.page))
+		} else {
+			it.prefetch = nil
+		}
+		return result.page, result.err
+	}
+
+	return it.client.Keys(ctx, it.keyRingIdentifier, it.offset, it.offset+it.opts.pageSize()-1)
+}
+
+func (it *KeyIterator) startPrefetch(ctx context.Context, offset int) {
+	it.prefetch = make(chan pagedKeys, 1)
+	go func(offset int) {
+		page, err := it.client.Keys(ctx, it.keyRingIdentifier, offset, offset+it.opts.pageSize()-1)
+		it.prefetch <- pagedKeys{page: page, err: err}
+	}(offset)
+}
+
+// Value returns the Key the most recent call to Next advanced to. It is only
+// valid after a call to Next that returned true.
+func (it *KeyIterator) Value() *Key {
+	return it.value
+}
+
+// Err returns the error, if any, that caused Next to return false. A nil Err
+// after Next returns false means the iterator was simply exhausted.
+func (it *KeyIterator) Err() error {
+	return it.err
+}
+
+// Close stops the iterator, discarding any in-flight prefetch.
+func (it *KeyIterator) Close() {
+	it.done = true
+	it.prefetch = nil
+}
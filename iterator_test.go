@@ -0,0 +1,103 @@
+package goaxle
+
+import (
+	"context"
+	"testing"
+)
+
+// fakeIterClient is a minimal KeyRingClient stub for exercising the
+// iterators. Only List and Keys are used by KeyRingIterator/KeyIterator; the
+// rest are left as nil method values and would panic if called.
+type fakeIterClient struct {
+	KeyRingClient
+
+	keyRings []*KeyRing
+	keys     []*Key
+}
+
+func (f *fakeIterClient) List(ctx context.Context, from int, to int) ([]*KeyRing, error) {
+	if from >= len(f.keyRings) {
+		return nil, nil
+	}
+	end := to + 1
+	if end > len(f.keyRings) {
+		end = len(f.keyRings)
+	}
+	return f.keyRings[from:end], nil
+}
+
+func (f *fakeIterClient) Keys(ctx context.Context, keyRingIdentifier string, from int, to int) ([]*Key, error) {
+	if from >= len(f.keys) {
+		return nil, nil
+	}
+	end := to + 1
+	if end > len(f.keys) {
+		end = len(f.keys)
+	}
+	return f.keys[from:end], nil
+}
+
+func makeKeyRings(n int) []*KeyRing {
+	out := make([]*KeyRing, n)
+	for i := range out {
+		out[i] = NewKeyRing(string(rune('a' + i)))
+	}
+	return out
+}
+
+func makeKeys(n int) []*Key {
+	out := make([]*Key, n)
+	for i := range out {
+		out[i] = &Key{Identifier: string(rune('a' + i))}
+	}
+	return out
+}
+
+func TestKeyRingIteratorWalksEveryPage(t *testing.T) {
+	client := &fakeIterClient{keyRings: makeKeyRings(5)}
+	it := IterKeyRings(client, IterOptions{PageSize: 2})
+
+	var got []string
+	for it.Next(context.Background()) {
+		got = append(got, it.Value().Identifier)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err() = %v, want nil", err)
+	}
+	if len(got) != 5 {
+		t.Fatalf("walked %d KeyRings, want 5", len(got))
+	}
+}
+
+func TestKeyIteratorWithPrefetchWalksEveryPage(t *testing.T) {
+	client := &fakeIterClient{keys: makeKeys(7)}
+	ring := NewKeyRing("ring-1")
+	it := ring.IterKeys(client, IterOptions{PageSize: 3, Prefetch: true})
+
+	var got []string
+	for it.Next(context.Background()) {
+		got = append(got, it.Value().Identifier)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err() = %v, want nil", err)
+	}
+	if len(got) != 7 {
+		t.Fatalf("walked %d Keys, want 7", len(got))
+	}
+}
+
+func TestKeyIteratorStopsOnCancelledContext(t *testing.T) {
+	client := &fakeIterClient{keys: makeKeys(3)}
+	ring := NewKeyRing("ring-1")
+	it := ring.IterKeys(client, IterOptions{PageSize: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if it.Next(ctx) {
+		t.Fatal("Next() = true on an already-cancelled context, want false")
+	}
+	if it.Err() == nil {
+		t.Fatal("Err() = nil after cancellation, want context.Canceled")
+	}
+}
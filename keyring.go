@@ -1,12 +1,24 @@
 package goaxle
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/url"
 	"time"
 )
 
+// KeyRing is the data ApiAxle stores about a keyring. The address of the
+// ApiAxle server it came from lives on a KeyRingClient instead, so a KeyRing
+// can be constructed, compared and passed around without any transport
+// attached; the convenience methods below just take a KeyRingClient to talk
+// to.
+//
+// Compatibility note: this moved the server address off KeyRing and onto an
+// explicit KeyRingClient parameter, so every instance method's signature
+// changed (e.g. Save() became Save(client), LinkKey(id) became
+// LinkKey(client, id)). That is source-breaking for existing callers of the
+// instance methods; it does not affect the package-level KeyRingXxx(...)
+// functions, which keep their original signatures.
 type KeyRing struct {
 	// Identifier is the name given to this KeyRing.  Modification not supported.
 	Identifier string `json:"-"`
@@ -19,90 +31,35 @@ type KeyRing struct {
 	// Use of this field is discouraged, use ParseUpdatedAt.
 	UpdatedAt float64 `json:"updatedAt,omitempty"`
 
-	// address where this keyring is located
-	axleAddress string
-	// do need to create a new keyring on save?
+	// do we need to create a new keyring on save?
 	createOnSave bool
 }
 
 // NewKeyRing creates a new KeyRing object with defaults.
-func NewKeyRing(axleAddress string, identifier string) (out *KeyRing) {
+func NewKeyRing(identifier string) (out *KeyRing) {
 	out = &KeyRing{
 		Identifier:   identifier,
-		axleAddress:  axleAddress,
 		createOnSave: true,
 	}
 	return out
 }
 
-// Create / Update this KeyRing on the ApiAxle server.
-// To modify an existing KeyRing, be sure to retrieve it with GetKeyRing, otherwise
-// the library will attempt to create a new KeyRing of the same name.
-func (this *KeyRing) Save() (err error) {
-	reqAddress := fmt.Sprintf(
-		"%s%skeyring/%s",
-		this.axleAddress,
-		VERSION_ENDPOINT,
-		url.QueryEscape(this.Identifier),
-	)
-
-	// update the updatedAt timestamp
-	this.UpdatedAt = float64(time.Now().UnixNano() / (1000 * 1000))
-	marshalled, err := json.Marshal(this)
-	if err != nil {
-		return fmt.Errorf("Unable to marshal KeyRing: %s", err.Error())
-	}
-
-	httpMethod := "POST"
-	if !this.createOnSave {
-		httpMethod = "PUT"
-		// TODO: why have an last updated field if you can't update it?
-		return fmt.Errorf("Unable to update key rings, it's not yet supported")
-	}
-
-	body, err := doHttpRequest(httpMethod, reqAddress, marshalled)
-	if err != nil {
-		return err
-	}
-
-	if !this.createOnSave {
-		err = populateKeyRingFromResponse(&this, body, []string{"results", "new"})
-	} else {
-		err = populateKeyRingFromResponse(&this, body, []string{"results"})
-	}
-
-	if err != nil {
-		return err
-	}
-
-	this.createOnSave = false
-
-	return nil
-}
-
-// GetKeyRing retrieves an existing api object from the server.
-func GetKeyRing(axleAddress string, identifier string) (out *KeyRing, err error) {
-
-	reqAddress := fmt.Sprintf("%s%skeyring/%s", axleAddress, VERSION_ENDPOINT, url.QueryEscape(identifier))
-	body, err := doHttpRequest("GET", reqAddress, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	// unmarshal into our new keyRing object
-	keyRing := NewKeyRing(axleAddress, identifier)
-	err = populateKeyRingFromResponse(&keyRing, body, []string{"results"})
+// String provides a JSON-like formated representation of this KeyRing object
+func (this *KeyRing) String() string {
+	out, err := json.MarshalIndent(this, "", "    ")
 	if err != nil {
-		return nil, err
+		return "<nil>"
 	}
-	keyRing.createOnSave = false
-
-	return keyRing, err
+	return fmt.Sprintf("KeyRing %q: %s", this.Identifier, string(out))
 }
 
 // populateKeyRingFromResponse updates the provided KeyRing pointer with the fields
 // provided in the response map.
 func populateKeyRingFromResponse(keyRing **KeyRing, body []byte, detailsLocation []string) (err error) {
+	if axleErr := parseAxleError(body); axleErr != nil {
+		return axleErr
+	}
+
 	response := make(map[string]interface{})
 	err = json.Unmarshal(body, &response)
 	if err != nil {
@@ -142,228 +99,177 @@ func populateKeyRingFromResponse(keyRing **KeyRing, body []byte, detailsLocation
 	return nil
 }
 
-// String provides a JSON-like formated representation of this KeyRing object
-func (this *KeyRing) String() string {
-	out, err := json.MarshalIndent(this, "", "    ")
-	if err != nil {
-		return "<nil>"
-	}
-	reqAddress := fmt.Sprintf(
-		"%s%skeyring/%s",
-		this.axleAddress,
-		VERSION_ENDPOINT,
-		url.QueryEscape(this.Identifier),
-	)
-	return fmt.Sprintf("KeyRing - %s: %s", reqAddress, string(out))
+// Create this KeyRing against client.
+// Updating an existing KeyRing is not supported: Save returns an error for a
+// KeyRing retrieved with client.Get or already saved. A KeyRing built with
+// NewKeyRing is always sent as a new KeyRing of that name.
+func (this *KeyRing) Save(client KeyRingClient) (err error) {
+	return this.SaveContext(context.Background(), client)
 }
 
-// DeleteKeyRing removes the identified KeyRing.  Any existing objects represting this
-// KeyRing will error on Save().
-func DeleteKeyRing(axleAddress string, identifier string) (err error) {
-	reqAddress := fmt.Sprintf("%s%skeyring/%s", axleAddress, VERSION_ENDPOINT, url.QueryEscape(identifier))
+// SaveContext is the context-aware variant of Save. The request is aborted if
+// ctx is cancelled or its deadline elapses before the ApiAxle server responds.
+func (this *KeyRing) SaveContext(ctx context.Context, client KeyRingClient) (err error) {
+	_, err = client.Save(ctx, this)
+	return err
+}
 
-	body, err := doHttpRequest("DELETE", reqAddress, nil)
-	if err != nil {
-		return err
-	}
+// Associate a key with this KeyRing.
+func (this *KeyRing) LinkKey(client KeyRingClient, keyIdentifier string) (key *Key, err error) {
+	return this.LinkKeyContext(context.Background(), client, keyIdentifier)
+}
 
-	responseMap := make(map[string]interface{})
-	err = json.Unmarshal(body, &responseMap)
-	if err != nil {
-		return fmt.Errorf(
-			"Unable to unmarshal response from %s: %s",
-			reqAddress,
-			err.Error(),
-		)
-	}
+// LinkKeyContext is the context-aware variant of LinkKey.
+func (this *KeyRing) LinkKeyContext(ctx context.Context, client KeyRingClient, keyIdentifier string) (key *Key, err error) {
+	return client.LinkKey(ctx, this.Identifier, keyIdentifier)
+}
 
-	// in this case, our result is what is contained in the "results" keyring
-	resultsInterface, exists := responseMap["results"]
-	if !exists {
-		return fmt.Errorf("Missing response from %s", reqAddress)
-	}
-	succeeded, isValidCast := resultsInterface.(bool)
-	if !isValidCast {
-		return fmt.Errorf(
-			"Unable to extract response object from %s",
-			reqAddress,
-		)
-	}
+// UnlinkKey disassociates the provided key from this KeyRing.
+func (this *KeyRing) UnlinkKey(client KeyRingClient, keyIdentifier string) (key *Key, err error) {
+	return this.UnlinkKeyContext(context.Background(), client, keyIdentifier)
+}
 
-	if !succeeded {
-		return fmt.Errorf("Delete of KeyRing at %s failed", reqAddress)
-	}
+// UnlinkKeyContext is the context-aware variant of UnlinkKey.
+func (this *KeyRing) UnlinkKeyContext(ctx context.Context, client KeyRingClient, keyIdentifier string) (key *Key, err error) {
+	return client.UnlinkKey(ctx, this.Identifier, keyIdentifier)
+}
 
-	return nil
+// List keys belonging to this KEYRING.
+func (this *KeyRing) Keys(client KeyRingClient, from int, to int) (keys []*Key, err error) {
+	return this.KeysContext(context.Background(), client, from, to)
 }
 
-// Associate a key with a KEYRING.
-func (this *KeyRing) LinkKey(keyIdentifier string) (key *Key, err error) {
-	return KeyRingLinkKey(this.axleAddress, this.Identifier, keyIdentifier)
+// KeysContext is the context-aware variant of Keys.
+func (this *KeyRing) KeysContext(ctx context.Context, client KeyRingClient, from int, to int) (keys []*Key, err error) {
+	return client.Keys(ctx, this.Identifier, from, to)
 }
 
-// Associate a key with a KEYRING.
-func KeyRingLinkKey(axleAddress string, keyRingIdentifier string, keyIdentifier string) (key *Key, err error) {
+// Get stats for this keyring
+func (this *KeyRing) Stats(client KeyRingClient, from time.Time, to time.Time, granularity Granularity) (stats map[HitType]map[time.Time]map[int]int, err error) {
+	return this.StatsContext(context.Background(), client, from, to, granularity)
+}
 
-	reqAddress := fmt.Sprintf(
-		"%s%skeyring/%s/linkkey/%s",
-		axleAddress,
-		VERSION_ENDPOINT,
-		url.QueryEscape(keyRingIdentifier),
-		url.QueryEscape(keyIdentifier),
-	)
+// StatsContext is the context-aware variant of Stats.
+func (this *KeyRing) StatsContext(ctx context.Context, client KeyRingClient, from time.Time, to time.Time, granularity Granularity) (stats map[HitType]map[time.Time]map[int]int, err error) {
+	return client.Stats(ctx, this.Identifier, from, to, "", "", granularity)
+}
 
-	body, err := doHttpRequest("PUT", reqAddress, []byte("{}"))
-	if err != nil {
-		return nil, err
-	}
+// Get stats for this keyring, scoped to a single key
+func (this *KeyRing) StatsForKey(client KeyRingClient, from time.Time, to time.Time, forkey string, granularity Granularity) (stats map[HitType]map[time.Time]map[int]int, err error) {
+	return this.StatsForKeyContext(context.Background(), client, from, to, forkey, granularity)
+}
 
-	key = NewKey(axleAddress, keyIdentifier)
-	err = populateKeyFromResponse(&key, body, []string{"results"})
-	if err != nil {
-		return nil, err
-	}
-	key.createOnSave = false
+// StatsForKeyContext is the context-aware variant of StatsForKey.
+func (this *KeyRing) StatsForKeyContext(ctx context.Context, client KeyRingClient, from time.Time, to time.Time, forkey string, granularity Granularity) (stats map[HitType]map[time.Time]map[int]int, err error) {
+	return client.Stats(ctx, this.Identifier, from, to, "", forkey, granularity)
+}
 
-	return key, nil
+// Get stats for this keyring, scoped to a single api
+func (this *KeyRing) StatsForApi(client KeyRingClient, from time.Time, to time.Time, forapi string, granularity Granularity) (stats map[HitType]map[time.Time]map[int]int, err error) {
+	return this.StatsForApiContext(context.Background(), client, from, to, forapi, granularity)
 }
 
-// UnlinkKey disassociates the provided key with this KeyRing.
-func (this *KeyRing) UnlinkKey(keyIdentifier string) (key *Key, err error) {
-	return KeyRingUnlinkKey(this.axleAddress, this.Identifier, keyIdentifier)
+// StatsForApiContext is the context-aware variant of StatsForApi.
+func (this *KeyRing) StatsForApiContext(ctx context.Context, client KeyRingClient, from time.Time, to time.Time, forapi string, granularity Granularity) (stats map[HitType]map[time.Time]map[int]int, err error) {
+	return client.Stats(ctx, this.Identifier, from, to, forapi, "", granularity)
 }
 
-// UnlinkKey disassociates the provided key with this API.
-func KeyRingUnlinkKey(axleAddress string, keyRingIdentifier string, keyIdentifier string) (key *Key, err error) {
-	reqAddress := fmt.Sprintf(
-		"%s%skeyring/%s/unlinkkey/%s",
-		axleAddress,
-		VERSION_ENDPOINT,
-		url.QueryEscape(keyRingIdentifier),
-		url.QueryEscape(keyIdentifier),
-	)
-
-	body, err := doHttpRequest("PUT", reqAddress, []byte("{}"))
-	if err != nil {
-		return nil, err
-	}
+// SaveKeyRing creates keyRing on the ApiAxle server at axleAddress. Updating
+// an existing KeyRing is not supported and returns an error.
+func SaveKeyRing(axleAddress string, keyRing *KeyRing) (err error) {
+	return SaveKeyRingContext(context.Background(), axleAddress, keyRing)
+}
 
-	key = NewKey(axleAddress, keyIdentifier)
-	err = populateKeyFromResponse(&key, body, []string{"results"})
-	if err != nil {
-		return nil, err
-	}
-	key.createOnSave = false
+// SaveKeyRingContext is the context-aware variant of SaveKeyRing. The request
+// is aborted if ctx is cancelled or its deadline elapses before the ApiAxle
+// server responds.
+func SaveKeyRingContext(ctx context.Context, axleAddress string, keyRing *KeyRing) (err error) {
+	return keyRing.SaveContext(ctx, NewKeyRingClient(axleAddress))
+}
 
-	return key, nil
+// GetKeyRing retrieves an existing api object from the server.
+func GetKeyRing(axleAddress string, identifier string) (out *KeyRing, err error) {
+	return GetKeyRingContext(context.Background(), axleAddress, identifier)
 }
 
-// List keys belonging to an KEYRING.
-func (this *KeyRing) Keys(from int, to int) (keys []*Key, err error) {
-	return KeyRingKeys(this.axleAddress, this.Identifier, from, to)
+// GetKeyRingContext is the context-aware variant of GetKeyRing. The request is
+// aborted if ctx is cancelled or its deadline elapses before the ApiAxle
+// server responds.
+func GetKeyRingContext(ctx context.Context, axleAddress string, identifier string) (out *KeyRing, err error) {
+	return NewKeyRingClient(axleAddress).Get(ctx, identifier)
 }
 
-// List keys belonging to an KEYRING.
-func KeyRingKeys(axleAddress string, identifier string, from int, to int) (keys []*Key, err error) {
+// DeleteKeyRing removes the identified KeyRing.  Any existing objects represting this
+// KeyRing will error on Save().
+func DeleteKeyRing(axleAddress string, identifier string) (err error) {
+	return DeleteKeyRingContext(context.Background(), axleAddress, identifier)
+}
 
-	reqAddress := fmt.Sprintf(
-		"%s%skeyring/%s/keys?resolve=true&from=%d&to=%d",
-		axleAddress,
-		VERSION_ENDPOINT,
-		url.QueryEscape(identifier),
-		from,
-		to,
-	)
+// DeleteKeyRingContext is the context-aware variant of DeleteKeyRing. The
+// request is aborted if ctx is cancelled or its deadline elapses before the
+// ApiAxle server responds.
+func DeleteKeyRingContext(ctx context.Context, axleAddress string, identifier string) (err error) {
+	return NewKeyRingClient(axleAddress).Delete(ctx, identifier)
+}
 
-	return doKeysRequest(reqAddress, axleAddress)
+// Associate a key with a KEYRING.
+func KeyRingLinkKey(axleAddress string, keyRingIdentifier string, keyIdentifier string) (key *Key, err error) {
+	return KeyRingLinkKeyContext(context.Background(), axleAddress, keyRingIdentifier, keyIdentifier)
 }
 
-// Get stats for an keyring
-func (this *KeyRing) Stats(from time.Time, to time.Time, granularity Granularity) (stats map[HitType]map[time.Time]map[int]int, err error) {
-	return KeyRingStats(this.axleAddress, this.Identifier, from, to, "", "", granularity)
+// KeyRingLinkKeyContext is the context-aware variant of KeyRingLinkKey. The
+// request is aborted if ctx is cancelled or its deadline elapses before the
+// ApiAxle server responds.
+func KeyRingLinkKeyContext(ctx context.Context, axleAddress string, keyRingIdentifier string, keyIdentifier string) (key *Key, err error) {
+	return NewKeyRingClient(axleAddress).LinkKey(ctx, keyRingIdentifier, keyIdentifier)
 }
 
-// Get stats for an keyring
-func (this *KeyRing) StatsForKey(from time.Time, to time.Time, forkey string, granularity Granularity) (stats map[HitType]map[time.Time]map[int]int, err error) {
-	return KeyRingStats(this.axleAddress, this.Identifier, from, to, forkey, "", granularity)
+// UnlinkKey disassociates the provided key with this API.
+func KeyRingUnlinkKey(axleAddress string, keyRingIdentifier string, keyIdentifier string) (key *Key, err error) {
+	return KeyRingUnlinkKeyContext(context.Background(), axleAddress, keyRingIdentifier, keyIdentifier)
 }
 
-// Get stats for an keyring
-func (this *KeyRing) StatsForApi(from time.Time, to time.Time, forapi string, granularity Granularity) (stats map[HitType]map[time.Time]map[int]int, err error) {
-	return KeyRingStats(this.axleAddress, this.Identifier, from, to, "", forapi, granularity)
+// KeyRingUnlinkKeyContext is the context-aware variant of KeyRingUnlinkKey.
+// The request is aborted if ctx is cancelled or its deadline elapses before
+// the ApiAxle server responds.
+func KeyRingUnlinkKeyContext(ctx context.Context, axleAddress string, keyRingIdentifier string, keyIdentifier string) (key *Key, err error) {
+	return NewKeyRingClient(axleAddress).UnlinkKey(ctx, keyRingIdentifier, keyIdentifier)
+}
+
+// List keys belonging to an KEYRING.
+func KeyRingKeys(axleAddress string, identifier string, from int, to int) (keys []*Key, err error) {
+	return KeyRingKeysContext(context.Background(), axleAddress, identifier, from, to)
+}
+
+// KeyRingKeysContext is the context-aware variant of KeyRingKeys. The request
+// is aborted if ctx is cancelled or its deadline elapses before the ApiAxle
+// server responds.
+func KeyRingKeysContext(ctx context.Context, axleAddress string, identifier string, from int, to int) (keys []*Key, err error) {
+	return NewKeyRingClient(axleAddress).Keys(ctx, identifier, from, to)
 }
 
 // Get stats for an keyring
 func KeyRingStats(axleAddress string, keyRingIdentifier string, from time.Time, to time.Time, forapi string, forkey string, granularity Granularity) (stats map[HitType]map[time.Time]map[int]int, err error) {
+	return KeyRingStatsContext(context.Background(), axleAddress, keyRingIdentifier, from, to, forapi, forkey, granularity)
+}
 
-	reqAddress := fmt.Sprintf(
-		"%s%skeyring/%s/stats?from=%d&to=%d&granularity=%s",
-		axleAddress,
-		VERSION_ENDPOINT,
-		url.QueryEscape(keyRingIdentifier),
-		from.Unix(),
-		to.Unix(),
-		granularity,
-	)
-
-	if forkey != "" {
-		reqAddress += "&forkey=" + url.QueryEscape(forkey)
-	}
-	if forapi != "" {
-		reqAddress += "&forapi=" + url.QueryEscape(forapi)
-	}
-
-	return doStatsRequest(reqAddress)
+// KeyRingStatsContext is the context-aware variant of KeyRingStats. The
+// request is aborted if ctx is cancelled or its deadline elapses before the
+// ApiAxle server responds.
+func KeyRingStatsContext(ctx context.Context, axleAddress string, keyRingIdentifier string, from time.Time, to time.Time, forapi string, forkey string, granularity Granularity) (stats map[HitType]map[time.Time]map[int]int, err error) {
+	return NewKeyRingClient(axleAddress).Stats(ctx, keyRingIdentifier, from, to, forapi, forkey, granularity)
 }
 
 // List all KEYRINGs.
 func KeyRings(axleAddress string, from int, to int) (out []*KeyRing, err error) {
-	reqAddress := fmt.Sprintf(
-		"%s%skeyrings?resolve=true&from=%d&to=%d",
-		axleAddress,
-		VERSION_ENDPOINT,
-		from,
-		to,
-	)
-
-	body, err := doHttpRequest("GET", reqAddress, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	response := make(map[string]interface{})
-	err = json.Unmarshal(body, &response)
-	if err != nil {
-		return nil, fmt.Errorf(
-			"Unable to unmarshal response: %s",
-			err.Error(),
-		)
-	}
-	response, validCast := response["results"].(map[string]interface{})
-	if !validCast {
-		return nil, fmt.Errorf(
-			"Unable to unmarshal response: %s",
-			err.Error(),
-		)
-	}
-	out = make([]*KeyRing, len(response))
-	x := 0
-	for identifier, value := range response {
-		keyring := NewKeyRing(axleAddress, identifier)
-		jsonvalue, err := json.Marshal(value)
-		if err != nil {
-			return nil, fmt.Errorf("Unable to decode keyring in response: %s", err.Error())
-		}
-		err = json.Unmarshal(jsonvalue, keyring)
-		if err != nil {
-			return nil, fmt.Errorf("Unable to decode keyring in response: %s", err.Error())
-		}
-		keyring.createOnSave = false
-		out[x] = keyring
-		x++
-	}
+	return KeyRingsContext(context.Background(), axleAddress, from, to)
+}
 
-	return out, nil
+// KeyRingsContext is the context-aware variant of KeyRings. The request is
+// aborted if ctx is cancelled or its deadline elapses before the ApiAxle
+// server responds.
+func KeyRingsContext(ctx context.Context, axleAddress string, from int, to int) (out []*KeyRing, err error) {
+	return NewKeyRingClient(axleAddress).List(ctx, from, to)
 }
 
 /* ex: set noexpandtab: */
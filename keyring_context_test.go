@@ -0,0 +1,25 @@
+package goaxle
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSaveKeyRingContextAbortsOnDeadlineExceeded(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte(`{"results":{"createdAt":1,"updatedAt":1}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := SaveKeyRingContext(ctx, srv.URL+"/", NewKeyRing("my-ring"))
+	if err == nil {
+		t.Fatal("SaveKeyRingContext() = nil, want an error once the deadline is exceeded")
+	}
+}
@@ -0,0 +1,59 @@
+package goaxle_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	goaxle "github.com/simonhoo/go-axle"
+	"github.com/simonhoo/go-axle/mocks"
+)
+
+func TestKeyRingSaveUsesMockClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mocks.NewMockKeyRingClient(ctrl)
+	ring := goaxle.NewKeyRing("my-ring")
+
+	client.EXPECT().
+		Save(gomock.Any(), ring).
+		Return(ring, nil)
+
+	if err := ring.Save(client); err != nil {
+		t.Fatalf("Save() = %v, want nil", err)
+	}
+}
+
+func TestKeyRingSavePropagatesClientError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mocks.NewMockKeyRingClient(ctrl)
+	ring := goaxle.NewKeyRing("my-ring")
+	wantErr := goaxle.ErrAlreadyExists
+
+	client.EXPECT().
+		Save(gomock.Any(), ring).
+		Return(nil, wantErr)
+
+	if err := ring.Save(client); err != wantErr {
+		t.Fatalf("Save() = %v, want %v", err, wantErr)
+	}
+}
+
+func TestKeyRingLinkKeyUsesMockClient(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := mocks.NewMockKeyRingClient(ctrl)
+	ring := goaxle.NewKeyRing("my-ring")
+
+	client.EXPECT().
+		LinkKey(gomock.Any(), "my-ring", "my-key").
+		Return(nil, nil)
+
+	if _, err := ring.LinkKeyContext(context.Background(), client, "my-key"); err != nil {
+		t.Fatalf("LinkKeyContext() = %v, want nil", err)
+	}
+}
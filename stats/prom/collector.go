@@ -0,0 +1,292 @@
+// Package prom exposes go-axle's KeyRing stats as a Prometheus Collector so
+// operators can scrape ApiAxle hit counts without hand-walking the
+// map[HitType]map[time.Time]map[int]int tree KeyRingStats returns.
+package prom
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	goaxle "github.com/simonhoo/go-axle"
+)
+
+const (
+	// defaultWindow is how far back a refresh looks when Config.Window is
+	// unset.
+	defaultWindow = 60 * time.Second
+	// defaultGranularity buckets the rolling window at one-second
+	// resolution, matching defaultWindow.
+	defaultGranularity = goaxle.Second
+	// keyListWindow bounds how many keys are listed per keyring when
+	// deciding whether a per-key label breakdown fits under
+	// Config.MaxKeyCardinality.
+	keyListWindow = 1000
+)
+
+var hitsDesc = prometheus.NewDesc(
+	"apiaxle_keyring_hits",
+	"ApiAxle hits observed for a keyring over the collector's rolling window. Not a counter: the window is fixed-length and fully replaced on each refresh, so the value can decrease between scrapes.",
+	[]string{"keyring", "api", "key", "hit_type", "status_code"},
+	nil,
+)
+
+// Config controls which keyrings a Collector scrapes and how.
+type Config struct {
+	// KeyRings is the set of keyring identifiers to report stats for.
+	KeyRings []string
+	// Apis, when non-empty, breaks each keyring's stats down per api in
+	// this list; no series for all apis combined is reported then. Empty
+	// means report stats across all apis combined (an empty "api" label).
+	Apis []string
+	// Window is how far back each refresh looks. Defaults to 60s.
+	Window time.Duration
+	// Granularity is the bucket size KeyRingStats is queried at. Defaults
+	// to goaxle.Second.
+	Granularity goaxle.Granularity
+	// MaxKeyCardinality caps the number of distinct "key" label values
+	// reported per keyring. Once a keyring has more linked keys than
+	// this, its samples collapse to a single ring-level series with an
+	// empty key label instead of one series per key. Zero means
+	// unlimited (always break down by key). Only the first keyListWindow
+	// (1000) linked keys are listed, so a cap of 1000 or more never trips
+	// and at most 1000 keys are broken down.
+	MaxKeyCardinality int
+}
+
+func (c Config) window() time.Duration {
+	if c.Window <= 0 {
+		return defaultWindow
+	}
+	return c.Window
+}
+
+func (c Config) granularity() goaxle.Granularity {
+	if c.Granularity == "" {
+		return defaultGranularity
+	}
+	return c.Granularity
+}
+
+// Collector implements prometheus.Collector over a fixed set of keyrings. Its
+// Collect method never itself calls ApiAxle; a Registerer refreshes the
+// cached stats on an interval so that scrapes stay cheap and bounded.
+type Collector struct {
+	client goaxle.KeyRingClient
+	cfg    Config
+
+	mu     sync.Mutex
+	cached []sample
+}
+
+type sample struct {
+	keyRing    string
+	api        string
+	key        string
+	hitType    goaxle.HitType
+	statusCode int
+	count      int
+}
+
+// NewCollector returns a Collector that queries client for the keyrings in
+// cfg. client is typically goaxle.NewKeyRingClient(axleAddress), but accepting
+// the interface directly lets callers substitute a fake or mocks.MockKeyRingClient
+// under test. Call Refresh (or start a Registerer) at least once before the
+// first scrape; until then Collect reports nothing.
+func NewCollector(client goaxle.KeyRingClient, cfg Config) *Collector {
+	return &Collector{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- hitsDesc
+}
+
+// Collect implements prometheus.Collector. It serves the stats from the most
+// recent Refresh rather than querying ApiAxle inline, so a scrape never waits
+// on the network.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	samples := c.cached
+	c.mu.Unlock()
+
+	for _, s := range samples {
+		ch <- prometheus.MustNewConstMetric(
+			hitsDesc,
+			prometheus.GaugeValue,
+			float64(s.count),
+			s.keyRing, s.api, s.key, string(s.hitType), fmt.Sprintf("%d", s.statusCode),
+		)
+	}
+}
+
+// Refresh queries ApiAxle for every configured keyring's stats over the
+// rolling window and replaces the cached samples Collect serves. It is safe
+// to call concurrently with Collect.
+func (c *Collector) Refresh(ctx context.Context) error {
+	now := time.Now()
+	from := now.Add(-c.cfg.window())
+
+	var samples []sample
+	for _, keyRing := range c.cfg.KeyRings {
+		ringSamples, err := c.refreshKeyRing(ctx, keyRing, from, now)
+		if err != nil {
+			return fmt.Errorf("refresh stats for keyring %q: %w", keyRing, err)
+		}
+		samples = append(samples, ringSamples...)
+	}
+
+	c.mu.Lock()
+	c.cached = samples
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (c *Collector) refreshKeyRing(ctx context.Context, keyRing string, from time.Time, to time.Time) ([]sample, error) {
+	keyLabels, err := c.keyLabelsFor(ctx, keyRing)
+	if err != nil {
+		return nil, err
+	}
+
+	apiLabels := c.cfg.Apis
+	if len(apiLabels) == 0 {
+		apiLabels = []string{""}
+	}
+
+	// Cardinality guard tripped: fall back to
+	// one ring-level series per api/hit_type/status_code instead of one
+	// per key.
+	if keyLabels == nil {
+		var samples []sample
+		for _, apiLabel := range apiLabels {
+			stats, err := c.client.Stats(ctx, keyRing, from, to, apiLabel, "", c.cfg.granularity())
+			if err != nil {
+				return nil, err
+			}
+			samples = append(samples, flatten(keyRing, apiLabel, "", stats)...)
+		}
+		return samples, nil
+	}
+
+	var samples []sample
+	for _, apiLabel := range apiLabels {
+		for _, keyLabel := range keyLabels {
+			stats, err := c.client.Stats(ctx, keyRing, from, to, apiLabel, keyLabel, c.cfg.granularity())
+			if err != nil {
+				return nil, err
+			}
+			samples = append(samples, flatten(keyRing, apiLabel, keyLabel, stats)...)
+		}
+	}
+	return samples, nil
+}
+
+// keyLabelsFor returns the key identifiers a refresh should break keyRing's
+// stats down by, or nil if the breakdown should be skipped because
+// MaxKeyCardinality was exceeded.
+func (c *Collector) keyLabelsFor(ctx context.Context, keyRing string) ([]string, error) {
+	keys, err := c.client.Keys(ctx, keyRing, 0, keyListWindow-1)
+	if err != nil {
+		return nil, err
+	}
+	if c.cfg.MaxKeyCardinality > 0 && len(keys) > c.cfg.MaxKeyCardinality {
+		return nil, nil
+	}
+
+	labels := make([]string, len(keys))
+	for i, key := range keys {
+		labels[i] = key.Identifier
+	}
+	return labels, nil
+}
+
+// flatten walks the map[HitType]map[time.Time]map[int]int tree KeyRingStats
+// returns and sums it down to one sample per (hit_type, status_code), all
+// tagged with apiLabel and keyLabel.
+func flatten(keyRing string, apiLabel string, keyLabel string, stats map[goaxle.HitType]map[time.Time]map[int]int) []sample {
+	type bucket struct {
+		hitType    goaxle.HitType
+		statusCode int
+	}
+	counts := make(map[bucket]int)
+	for hitType, byTime := range stats {
+		for _, byStatus := range byTime {
+			for statusCode, count := range byStatus {
+				counts[bucket{hitType, statusCode}] += count
+			}
+		}
+	}
+
+	samples := make([]sample, 0, len(counts))
+	for b, count := range counts {
+		samples = append(samples, sample{
+			keyRing:    keyRing,
+			api:        apiLabel,
+			key:        keyLabel,
+			hitType:    b.hitType,
+			statusCode: b.statusCode,
+			count:      count,
+		})
+	}
+	return samples
+}
+
+// Registerer periodically calls Refresh on a Collector so scrapes read from
+// an in-memory cache instead of blocking on ApiAxle.
+type Registerer struct {
+	collector *Collector
+	interval  time.Duration
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewRegisterer registers collector with reg and returns a Registerer that
+// must be started with Start to begin refreshing it.
+func NewRegisterer(reg prometheus.Registerer, collector *Collector, interval time.Duration) (*Registerer, error) {
+	if err := reg.Register(collector); err != nil {
+		return nil, err
+	}
+	return &Registerer{
+		collector: collector,
+		interval:  interval,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}, nil
+}
+
+// Start runs Refresh once immediately and then on every interval until Stop
+// is called. It must only be called once per Registerer.
+func (r *Registerer) Start(ctx context.Context) {
+	go func() {
+		defer close(r.done)
+
+		r.collector.Refresh(ctx)
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				r.collector.Refresh(ctx)
+			case <-r.stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the background refresher and waits for it to exit.
+func (r *Registerer) Stop() {
+	close(r.stop)
+	<-r.done
+}
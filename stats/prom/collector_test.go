@@ -0,0 +1,107 @@
+package prom
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	goaxle "github.com/simonhoo/go-axle"
+)
+
+// fakeClient is a minimal goaxle.KeyRingClient stub covering what Collector.
+// Refresh calls: Keys and Stats.
+type fakeClient struct {
+	goaxle.KeyRingClient
+
+	keys  []*goaxle.Key
+	stats map[string]map[goaxle.HitType]map[time.Time]map[int]int // keyed by forapi+"|"+forkey
+}
+
+func (f *fakeClient) Keys(ctx context.Context, keyRingIdentifier string, from int, to int) ([]*goaxle.Key, error) {
+	return f.keys, nil
+}
+
+func (f *fakeClient) Stats(ctx context.Context, keyRingIdentifier string, from time.Time, to time.Time, forapi string, forkey string, granularity goaxle.Granularity) (map[goaxle.HitType]map[time.Time]map[int]int, error) {
+	return f.stats[forapi+"|"+forkey], nil
+}
+
+func TestCollectorRefreshBreaksDownByApi(t *testing.T) {
+	now := time.Now()
+	client := &fakeClient{
+		// Two linked keys against a MaxKeyCardinality of 1 trips the
+		// cardinality guard, so Refresh takes the ring-level (forkey="")
+		// path below rather than querying Stats once per key.
+		keys: []*goaxle.Key{{Identifier: "k1"}, {Identifier: "k2"}},
+		stats: map[string]map[goaxle.HitType]map[time.Time]map[int]int{
+			"api-a|": {
+				goaxle.HitType("accepted"): {now: {200: 3}},
+			},
+			"api-b|": {
+				goaxle.HitType("accepted"): {now: {200: 5}},
+			},
+		},
+	}
+
+	c := NewCollector(client, Config{
+		KeyRings:          []string{"ring-1"},
+		Apis:              []string{"api-a", "api-b"},
+		MaxKeyCardinality: 1,
+	})
+
+	if err := c.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() = %v, want nil", err)
+	}
+
+	c.mu.Lock()
+	samples := append([]sample(nil), c.cached...)
+	c.mu.Unlock()
+
+	byApi := map[string]int{}
+	for _, s := range samples {
+		byApi[s.api] += s.count
+	}
+	if byApi["api-a"] != 3 || byApi["api-b"] != 5 {
+		t.Fatalf("got per-api counts %v, want api-a=3 api-b=5", byApi)
+	}
+}
+
+func TestCollectorRefreshRespectsMaxKeyCardinality(t *testing.T) {
+	now := time.Now()
+	client := &fakeClient{
+		keys: []*goaxle.Key{{Identifier: "k1"}, {Identifier: "k2"}},
+		stats: map[string]map[goaxle.HitType]map[time.Time]map[int]int{
+			"|": {goaxle.HitType("accepted"): {now: {200: 9}}},
+		},
+	}
+
+	c := NewCollector(client, Config{
+		KeyRings:          []string{"ring-1"},
+		MaxKeyCardinality: 1,
+	})
+
+	if err := c.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() = %v, want nil", err)
+	}
+
+	c.mu.Lock()
+	samples := append([]sample(nil), c.cached...)
+	c.mu.Unlock()
+
+	if len(samples) != 1 || samples[0].key != "" {
+		t.Fatalf("got samples %+v, want a single ring-level sample with an empty key label", samples)
+	}
+}
+
+func TestRegistererStopWaitsForRefresher(t *testing.T) {
+	client := &fakeClient{}
+	c := NewCollector(client, Config{KeyRings: []string{"ring-1"}})
+
+	reg, err := NewRegisterer(prometheus.NewRegistry(), c, time.Hour)
+	if err != nil {
+		t.Fatalf("NewRegisterer() = %v, want nil", err)
+	}
+
+	reg.Start(context.Background())
+	reg.Stop()
+}